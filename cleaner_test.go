@@ -0,0 +1,71 @@
+package rotatefile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeAged(t *testing.T, path string, age time.Duration, now time.Time) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	mt := now.Add(-age)
+	if err := os.Chtimes(path, mt, mt); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCleanerDedupesOverlappingPatterns(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2026, time.July, 29, 0, 0, 0, 0, time.UTC)
+
+	writeAged(t, filepath.Join(dir, "app.log.1"), 3*time.Hour, now)
+	writeAged(t, filepath.Join(dir, "app.log.2.gz"), 2*time.Hour, now)
+	writeAged(t, filepath.Join(dir, "app.log.3"), time.Hour, now)
+
+	// "*.log.*" already matches the ".gz" backups that "*.log.*.gz" also matches, so both are registered here on
+	// purpose to exercise the overlap.
+	c := &Cleaner{Clock: fixedClock{t: now}, MaxBackups: 2}
+	c.AddDir(dir)
+	c.AddPattern("*.log.*")
+	c.AddPattern("*.log.*.gz")
+
+	if err := c.Run(); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	remaining, err := filepath.Glob(filepath.Join(dir, "app.log.*"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(remaining) != 2 {
+		t.Fatalf("expected 2 files to survive MaxBackups=2, got %d: %v", len(remaining), remaining)
+	}
+}
+
+func TestCleanerMaxAge(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2026, time.July, 29, 0, 0, 0, 0, time.UTC)
+
+	old := filepath.Join(dir, "app.log.1")
+	writeAged(t, old, 48*time.Hour, now)
+	fresh := filepath.Join(dir, "app.log.2")
+	writeAged(t, fresh, time.Hour, now)
+
+	c := &Cleaner{Clock: fixedClock{t: now}, MaxAge: 24 * time.Hour}
+	c.AddDir(dir)
+	c.AddPattern("*.log.*")
+
+	if err := c.Run(); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if isFileExist(old) {
+		t.Fatalf("expected %q to be pruned by MaxAge", old)
+	}
+	if !isFileExist(fresh) {
+		t.Fatalf("expected %q to survive MaxAge", fresh)
+	}
+}
@@ -0,0 +1,15 @@
+package rotatefile
+
+import "time"
+
+// Clock abstracts time.Now so that time-driven rotation and cleanup can be tested deterministically.
+type Clock interface {
+	Now() time.Time
+}
+
+// RealClock implements Clock using the system clock.
+type RealClock struct{}
+
+func (RealClock) Now() time.Time {
+	return time.Now()
+}
@@ -0,0 +1,12 @@
+package rotatefile
+
+import "time"
+
+// fixedClock is a Clock that always reports t, making time-driven rotation and cleanup deterministic in tests.
+type fixedClock struct {
+	t time.Time
+}
+
+func (c fixedClock) Now() time.Time {
+	return c.t
+}
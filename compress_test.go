@@ -0,0 +1,38 @@
+package rotatefile
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestRotateFileCompressesBackup(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "app.log")
+
+	f, err := New(name, 1, 10, true, nil, RotateModeRename)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	// 11 bytes against a 10-byte CapLimit rotates exactly once, renaming the backup to "app.log.1" before
+	// Compress kicks in.
+	if _, err := f.Write([]byte("hello world")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	gz := name + ".1.gz"
+	if !isFileExist(gz) {
+		t.Fatalf("expected %q to exist after compression", gz)
+	}
+	if isFileExist(name + ".1") {
+		t.Fatalf("expected uncompressed backup %q to be removed once compressed", name+".1")
+	}
+	select {
+	case err := <-f.CompressErr:
+		t.Fatalf("unexpected compression error: %v", err)
+	default:
+	}
+}
@@ -1,11 +1,12 @@
 package rotatefile
 
 import (
+	"compress/gzip"
 	"fmt"
+	"io"
 	"os"
+	"sync"
 	"time"
-
-	"github.com/mohanson/doa"
 )
 
 // Test whether a path exists. Returns False for broken symbolic links.
@@ -14,15 +15,47 @@ func isFileExist(name string) bool {
 	return err == nil
 }
 
+// clockNow returns f.Clock.Now(), falling back to the system clock if Clock was never set (e.g. a zero-value
+// RotateFile built by hand rather than through New).
+func (f *RotateFile) clockNow() time.Time {
+	if f.Clock == nil {
+		return time.Now()
+	}
+	return f.Clock.Now()
+}
+
+// RotateMode selects what happens to the active file on rollover.
+type RotateMode int
+
+const (
+	// RotateModeRename is the default: the active file is always Name, and backups shift name.1 -> name.2 etc. via a
+	// rename cascade.
+	RotateModeRename RotateMode = iota
+	// RotateModeCreate skips the rename cascade. On rollover the active file is closed and a new file named
+	// "name.<timestamp>" is opened directly, leaving every prior file under its original name (with Compress set, the
+	// closed file is gzipped to that name plus ".gz"). This avoids an O(Backup) rename storm on every rollover, and the
+	// resulting filenames sort naturally by time. Backup is ignored in this mode; use a Cleaner for retention instead.
+	RotateModeCreate
+)
+
 // Handler for logging to a set of files, which switches from one file to the next when the current file reaches a
-// certain size.
+// certain size. RotateFile is safe for concurrent use by multiple goroutines calling Write and Close.
 type RotateFile struct {
-	Backup   int
-	CapLimit int
-	CapUsing int
-	File     *os.File
-	Name     string
-	UpdateAt time.Time
+	Backup      int
+	CapLimit    int
+	CapUsing    int
+	Cleaner     *Cleaner
+	Clock       Clock
+	Compress    bool
+	CompressErr chan error
+	File        *os.File
+	Mode        RotateMode
+	Name        string
+	UpdateAt    time.Time
+
+	base string         // original Name passed to New; RotateModeCreate derives new filenames from this
+	mu   sync.Mutex     // held across the full write path, including the rename cascade and reopen
+	wg   sync.WaitGroup // tracks in-flight background compression goroutines
 }
 
 // Open with flag os.O_WRONLY + os.O_TRUNC
@@ -45,41 +78,98 @@ func (f *RotateFile) OpenWronlyCreateAppend() error {
 	return nil
 }
 
+// write writes b, rotating as many times as necessary to keep CapUsing within CapLimit. A single call may rotate
+// more than once if len(b) itself exceeds CapLimit, so it loops rather than assuming one rotation always suffices.
 func (f *RotateFile) write(b []byte) (n int, err error) {
-	capSpace := f.CapLimit - f.CapUsing
-	if capSpace >= len(b) {
-		n, err = f.File.Write(b)
-		f.CapUsing += n
+	// CapLimit <= 0 means unbounded growth: rollover never occurs, so skip the capacity bookkeeping below entirely
+	// rather than let capSpace go negative.
+	if f.CapLimit <= 0 {
+		var wn int
+		wn, err = f.File.Write(b)
+		n += wn
+		f.CapUsing += wn
 		f.UpdateAt = time.Now()
 		return
 	}
-	writeN := 0
-	writeN, err = f.File.Write(b[:capSpace])
-	n += writeN
-	f.CapUsing += writeN
-	f.UpdateAt = time.Now()
-	if err != nil {
-		return
+
+	for {
+		capSpace := f.CapLimit - f.CapUsing
+		if capSpace < 0 {
+			capSpace = 0
+		}
+		if capSpace >= len(b) {
+			var wn int
+			wn, err = f.File.Write(b)
+			n += wn
+			f.CapUsing += wn
+			f.UpdateAt = time.Now()
+			return
+		}
+
+		if capSpace > 0 {
+			var wn int
+			wn, err = f.File.Write(b[:capSpace])
+			n += wn
+			f.CapUsing += wn
+			f.UpdateAt = time.Now()
+			if err != nil {
+				return
+			}
+			b = b[capSpace:]
+		}
+
+		if err = f.rotate(); err != nil {
+			return
+		}
 	}
+}
 
-	err = f.File.Close()
-	if err != nil {
+// rotate closes the current file, applies Mode's rollover behavior, and reopens a fresh file with CapUsing reset to
+// zero.
+func (f *RotateFile) rotate() (err error) {
+	if err = f.File.Close(); err != nil {
 		return
 	}
 
-	// Rollover occurs whenever the current log file is nearly maxBytes in length. If backupCount is >= 1, the system
-	// will successively create new files with the same pathname as the base file, but with extensions ".1", ".2" etc.
-	// appended to it. For example, with a backupCount of 5 and a base file name of "app.log", you would get "app.log",
-	// "app.log.1", "app.log.2", ... through to "app.log.5". The file being written to is always "app.log" - when it
-	// gets filled up, it is closed and renamed to "app.log.1", and if files "app.log.1", "app.log.2" etc. exist, then
-	// they are renamed to "app.log.2", "app.log.3" etc. respectively.
-	//
-	// If maxBytes is zero, rollover never occurs.
-	if f.Backup > 0 {
+	// Rollover occurs whenever the current log file is nearly maxBytes in length. What happens to the just-closed file
+	// depends on Mode.
+	if f.Mode == RotateModeCreate {
+		// RotateModeCreate: leave the just-closed file exactly where it is, and point Name at a fresh,
+		// timestamp-suffixed file for the reopen below. A single Write can rotate more than once within the same
+		// wall-clock second (e.g. a write much larger than CapLimit), so the timestamp alone doesn't guarantee a
+		// fresh name - fall back to an incrementing suffix until we land on one that doesn't already exist.
+		closed := f.Name
+		stamp := fmt.Sprintf("%s.%s", f.base, f.clockNow().Format("20060102150405"))
+		f.Name = stamp
+		// A prior file may already have been compressed away to name+".gz", so that name counts as taken too.
+		for i := 1; isFileExist(f.Name) || isFileExist(f.Name+".gz"); i++ {
+			f.Name = fmt.Sprintf("%s.%d", stamp, i)
+		}
+		if f.Compress {
+			f.compressAsync(closed)
+		}
+	} else if f.Backup > 0 {
+		// RotateModeRename (default): if backupCount is >= 1, the system will successively create new files with the
+		// same pathname as the base file, but with extensions ".1", ".2" etc. appended to it. For example, with a
+		// backupCount of 5 and a base file name of "app.log", you would get "app.log", "app.log.1", "app.log.2", ...
+		// through to "app.log.5". The file being written to is always "app.log" - when it gets filled up, it is
+		// closed and renamed to "app.log.1", and if files "app.log.1", "app.log.2" etc. exist, then they are renamed
+		// to "app.log.2", "app.log.3" etc. respectively.
 		for i := f.Backup - 1; i > 0; i-- {
 			sfn := fmt.Sprintf("%s.%d", f.Name, i)
 			dfn := fmt.Sprintf("%s.%d", f.Name, i+1)
-			if isFileExist(sfn) {
+			// A backup may have been compressed in place, so the shift must look for "name.i.gz" first and fall back
+			// to the uncompressed "name.i".
+			if isFileExist(sfn + ".gz") {
+				if isFileExist(dfn + ".gz") {
+					if err = os.Remove(dfn + ".gz"); err != nil {
+						return
+					}
+				}
+				if err = os.Rename(sfn+".gz", dfn+".gz"); err != nil {
+					return
+				}
+			} else if isFileExist(sfn) {
 				if isFileExist(dfn) {
 					err = os.Remove(dfn)
 					if err != nil {
@@ -99,43 +189,118 @@ func (f *RotateFile) write(b []byte) (n int, err error) {
 				return
 			}
 		}
+		if isFileExist(dfn + ".gz") {
+			if err = os.Remove(dfn + ".gz"); err != nil {
+				return
+			}
+		}
 		err = os.Rename(f.Name, dfn)
 		if err != nil {
 			return
 		}
+		if f.Compress {
+			f.compressAsync(dfn)
+		}
 	}
 
-	err = f.OpenWronlyCreateAppend()
-	if err != nil {
+	if err = f.OpenWronlyCreateAppend(); err != nil {
 		return
 	}
-	writeN, err = f.File.Write(b[capSpace:])
-	n += writeN
-	f.CapUsing = writeN
-	f.UpdateAt = time.Now()
+	f.CapUsing = 0
+
+	if f.Cleaner != nil {
+		err = f.Cleaner.Run()
+	}
 	return
 }
 
-// Panic directly to avoid errors being eaten.
+// compressAsync gzips name to name+".gz" in a background goroutine and removes name on success. It must run off the
+// write path since gzip cost should never block callers of Write. Close blocks until it finishes, and a failure is
+// reported on CompressErr instead of panicking; the send never blocks, so an error is dropped when CompressErr is nil
+// or still holds an earlier unread error.
+func (f *RotateFile) compressAsync(name string) {
+	f.wg.Add(1)
+	go func() {
+		defer f.wg.Done()
+		if err := compressFile(name); err != nil {
+			select {
+			case f.CompressErr <- err:
+			default:
+			}
+		}
+	}()
+}
+
+// compressFile gzips name to name+".gz" and removes name on success.
+func compressFile(name string) error {
+	src, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(name+".gz", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+
+	gw := gzip.NewWriter(dst)
+	if _, err := io.Copy(gw, src); err != nil {
+		gw.Close()
+		dst.Close()
+		return err
+	}
+	if err := gw.Close(); err != nil {
+		dst.Close()
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		return err
+	}
+	return os.Remove(name)
+}
+
+// Write writes b to the current file, rotating first if it would overflow CapLimit. It holds f.mu for the full write
+// path, so concurrent callers are serialized rather than racing on CapUsing, the rename cascade, and File.
 func (f *RotateFile) Write(b []byte) (n int, err error) {
-	n = doa.Try2(f.write(b)).(int)
-	return n, nil
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.write(b)
 }
 
-// Close closes the File.
+// Close closes the File. It blocks until any in-flight background compression has finished, so callers can rely on
+// every backup being fully compressed once Close returns.
 func (f *RotateFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.wg.Wait()
 	return f.File.Close()
 }
 
 // Open the specified file and use it as the stream for logging.
 //
 // By default, the file grows indefinitely. You can specify particular values of maxBytes and backupCount to allow the
-// file to rollover at a predetermined size.
-func New(name string, backup int, size int) (*RotateFile, error) {
+// file to rollover at a predetermined size. Set compress to gzip each backup in the background as it is produced.
+// mode selects what happens to the active file on rollover; pass RotateModeRename for the traditional ".1", ".2" ...
+// backups, or RotateModeCreate to instead open a new timestamp-suffixed file each time and leave retention to a
+// Cleaner.
+//
+// cleaner is optional; pass nil if a hard Backup count already expresses your retention policy. When set,
+// RotateFile runs it once after every rollover in addition to whatever schedule you give cleaner.RunEvery, so it can
+// also express policies a Backup count can't (e.g. "keep 30 days").
+func New(name string, backup int, size int, compress bool, cleaner *Cleaner, mode RotateMode) (*RotateFile, error) {
 	r := &RotateFile{
 		Backup:   backup,
 		CapLimit: size,
+		Cleaner:  cleaner,
+		Clock:    RealClock{},
+		Compress: compress,
+		Mode:     mode,
 		Name:     name,
+		base:     name,
+	}
+	if compress {
+		r.CompressErr = make(chan error, 1)
 	}
 	if err := r.OpenWronlyCreateAppend(); err != nil {
 		return r, err
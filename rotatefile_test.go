@@ -0,0 +1,56 @@
+package rotatefile
+
+import (
+	"bytes"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestRotateFileWriteHandlesOversizedWrites(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "app.log")
+
+	f, err := New(name, 10, 5, false, nil, RotateModeRename)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer f.Close()
+
+	// 37 bytes against a 5-byte CapLimit forces several rotations within one Write call; it used to panic slicing
+	// the remainder with a negative index.
+	b := bytes.Repeat([]byte("x"), 37)
+	n, err := f.Write(b)
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if n != len(b) {
+		t.Fatalf("n = %d, want %d", n, len(b))
+	}
+	if f.CapUsing < 0 || f.CapUsing > f.CapLimit {
+		t.Fatalf("CapUsing = %d, want a value in [0, %d]", f.CapUsing, f.CapLimit)
+	}
+}
+
+func TestRotateFileWriteIsConcurrencySafe(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "app.log")
+
+	f, err := New(name, 5, 50, false, nil, RotateModeRename)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer f.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := f.Write([]byte("0123456789")); err != nil {
+				t.Errorf("Write: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+}
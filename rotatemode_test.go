@@ -0,0 +1,77 @@
+package rotatefile
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRotateModeCreateProducesDistinctFilesWithinSameSecond(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "app.log")
+
+	f, err := New(name, 0, 5, false, nil, RotateModeCreate)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer f.Close()
+
+	// 37 bytes against a 5-byte CapLimit forces several rotations inside one Write call, all within the same
+	// wall-clock second - each must land on a distinct file instead of merging into the last one.
+	b := bytes.Repeat([]byte("x"), 37)
+	if _, err := f.Write(b); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	rotated, err := filepath.Glob(name + ".*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rotated) < 6 {
+		t.Fatalf("expected at least 6 distinct rotated files, got %d: %v", len(rotated), rotated)
+	}
+
+	all, err := filepath.Glob(filepath.Join(dir, "app.log*"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var total int64
+	for _, m := range all {
+		s, err := os.Stat(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		total += s.Size()
+	}
+	if total != int64(len(b)) {
+		t.Fatalf("total bytes across rotated files = %d, want %d", total, len(b))
+	}
+}
+
+func TestRotateModeCreateCompressesClosedFile(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "app.log")
+
+	f, err := New(name, 0, 10, true, nil, RotateModeCreate)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	// 11 bytes against a 10-byte CapLimit rotates exactly once, so the original "app.log" is the file Compress
+	// should act on.
+	if _, err := f.Write([]byte("hello world")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	gz := name + ".gz"
+	if !isFileExist(gz) {
+		t.Fatalf("expected %q to exist after compression", gz)
+	}
+	if isFileExist(name) {
+		t.Fatalf("expected closed file %q to be removed once compressed", name)
+	}
+}
@@ -0,0 +1,193 @@
+package rotatefile
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// strftimeDirectives maps the subset of strftime directives TimeRotateFile patterns accept to the Go reference-time
+// layout token that formats the same field. strftimeOrder fixes the iteration order used when scanning a pattern.
+var strftimeDirectives = map[string]string{
+	"%Y": "2006",
+	"%m": "01",
+	"%d": "02",
+	"%H": "15",
+	"%M": "04",
+	"%S": "05",
+}
+var strftimeOrder = []string{"%Y", "%m", "%d", "%H", "%M", "%S"}
+
+// expandPattern formats t using a strftime-style pattern such as "app.%Y%m%d.log". Only recognized directives are
+// formatted; every other byte is copied through verbatim. This matters because literal digits in the pattern (a port
+// number, a version, an instance id) can coincide with Go reference-time tokens ("1", "2", "15", ...) - running the
+// whole pattern through time.Format, rather than formatting just the matched directive spans, would reinterpret and
+// corrupt them.
+func expandPattern(pattern string, t time.Time) string {
+	var out strings.Builder
+	for i := 0; i < len(pattern); {
+		if pattern[i] == '%' {
+			if layout, ok := matchDirective(pattern[i:]); ok {
+				out.WriteString(t.Format(strftimeDirectives[layout]))
+				i += len(layout)
+				continue
+			}
+		}
+		out.WriteByte(pattern[i])
+		i++
+	}
+	return out.String()
+}
+
+// matchDirective reports whether s starts with a known strftime directive, returning that directive.
+func matchDirective(s string) (string, bool) {
+	for _, d := range strftimeOrder {
+		if strings.HasPrefix(s, d) {
+			return d, true
+		}
+	}
+	return "", false
+}
+
+// globPattern turns a strftime-style pattern into a glob that matches every file the pattern could ever expand to,
+// e.g. "app.%Y%m%d.log" becomes "app.*.log".
+func globPattern(pattern string) string {
+	g := pattern
+	for _, d := range strftimeOrder {
+		g = strings.ReplaceAll(g, d, "*")
+	}
+	for strings.Contains(g, "**") {
+		g = strings.ReplaceAll(g, "**", "*")
+	}
+	return g
+}
+
+// TimeRotateFile is a sibling of RotateFile that rotates on wall-clock intervals rather than size. The active
+// filename is derived by expanding a strftime-style Pattern with the current time, so "app.%Y%m%d.log" yields one
+// file per day and "app.%Y%m%d%H.log" yields one per hour. Like RotateFile, it is safe for concurrent use by
+// multiple goroutines calling Write and Close.
+type TimeRotateFile struct {
+	Clock   Clock
+	File    *os.File
+	GenAt   time.Time
+	MaxAge  time.Duration
+	Name    string
+	Pattern string
+	Period  time.Duration
+	Symlink string
+
+	mu sync.Mutex // held across the full write path, including rollover and reopen
+}
+
+// currentGen returns the start of the rotation period containing t.
+func (f *TimeRotateFile) currentGen(t time.Time) time.Time {
+	return t.Truncate(f.Period)
+}
+
+// open opens f.Name, refreshes Symlink if set, and prunes files older than MaxAge.
+func (f *TimeRotateFile) open() error {
+	r, err := os.OpenFile(f.Name, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	f.File = r
+
+	if f.Symlink != "" {
+		_ = os.Remove(f.Symlink)
+		if err := os.Symlink(f.Name, f.Symlink); err != nil {
+			return err
+		}
+	}
+	return f.cleanMaxAge()
+}
+
+// cleanMaxAge removes files matching Pattern's glob expansion whose mtime is older than MaxAge. It is a no-op when
+// MaxAge is zero.
+func (f *TimeRotateFile) cleanMaxAge() error {
+	if f.MaxAge <= 0 {
+		return nil
+	}
+	matches, err := filepath.Glob(globPattern(f.Pattern))
+	if err != nil {
+		return err
+	}
+	cutoff := f.Clock.Now().Add(-f.MaxAge)
+	for _, m := range matches {
+		if m == f.Name {
+			continue
+		}
+		s, err := os.Stat(m)
+		if err != nil {
+			continue
+		}
+		if s.ModTime().Before(cutoff) {
+			if err := os.Remove(m); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// rollover closes the currently open file (if any), advances GenAt and Name to the period containing t, and opens
+// the new file.
+func (f *TimeRotateFile) rollover(t time.Time) error {
+	if f.File != nil {
+		if err := f.File.Close(); err != nil {
+			return err
+		}
+	}
+	f.GenAt = f.currentGen(t)
+	f.Name = expandPattern(f.Pattern, f.GenAt)
+	return f.open()
+}
+
+// Write rolls over to the next period's file when the wall clock has crossed into it, then writes b. It holds f.mu
+// for the full path, so concurrent callers are serialized rather than racing on GenAt, Name, and File.
+func (f *TimeRotateFile) Write(b []byte) (n int, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	now := f.Clock.Now()
+	if f.File == nil || !f.currentGen(now).Equal(f.GenAt) {
+		if err = f.rollover(now); err != nil {
+			return
+		}
+	}
+	return f.File.Write(b)
+}
+
+// Close closes the File.
+func (f *TimeRotateFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.File.Close()
+}
+
+// NewTimeRotateFile opens (or creates) the file that pattern expands to at the current time, and rotates to a new
+// file whenever the wall clock crosses into the next period.
+//
+// Pattern is a strftime-style template, e.g. "app.%Y%m%d.log" for daily rotation or "app.%Y%m%d%H.log" for hourly.
+// symlink, if non-empty, is a path that always points at the currently open file. maxAge, if non-zero, prunes files
+// matching Pattern's glob expansion once they are older than the given duration. Both take effect starting with this
+// first file - they must be passed in rather than set on the returned value, since the latter would have no effect
+// until the next rollover. clock is optional; pass nil to use the system clock, or inject one to control the initial
+// file's name and MaxAge cutoff deterministically in tests.
+func NewTimeRotateFile(pattern string, period time.Duration, symlink string, maxAge time.Duration, clock Clock) (*TimeRotateFile, error) {
+	if clock == nil {
+		clock = RealClock{}
+	}
+	r := &TimeRotateFile{
+		Clock:   clock,
+		MaxAge:  maxAge,
+		Pattern: pattern,
+		Period:  period,
+		Symlink: symlink,
+	}
+	if err := r.rollover(r.Clock.Now()); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
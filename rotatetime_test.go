@@ -0,0 +1,71 @@
+package rotatefile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestExpandPatternLeavesLiteralDigitsAlone(t *testing.T) {
+	ts := time.Date(2026, time.July, 29, 0, 0, 0, 0, time.UTC)
+	got := expandPattern("/tmp/demo_server1/app.%Y%m%d.log", ts)
+	want := "/tmp/demo_server1/app.20260729.log"
+	if got != want {
+		t.Fatalf("expandPattern() = %q, want %q", got, want)
+	}
+}
+
+func TestTimeRotateFileRollsOverOnPeriodBoundary(t *testing.T) {
+	dir := t.TempDir()
+	pattern := filepath.Join(dir, "app.%Y%m%d%H.log")
+	clock := &fixedClock{t: time.Date(2026, time.July, 29, 10, 0, 0, 0, time.UTC)}
+
+	f, err := NewTimeRotateFile(pattern, time.Hour, "", 0, clock)
+	if err != nil {
+		t.Fatalf("NewTimeRotateFile: %v", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write([]byte("a")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	first := f.Name
+
+	clock.t = clock.t.Add(time.Hour)
+	if _, err := f.Write([]byte("b")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if f.Name == first {
+		t.Fatalf("expected rollover onto a new file once the clock crossed an hour boundary, still writing %q", f.Name)
+	}
+	if !isFileExist(first) {
+		t.Fatalf("expected prior file %q to still exist after rollover", first)
+	}
+}
+
+func TestTimeRotateFileMaxAge(t *testing.T) {
+	dir := t.TempDir()
+	pattern := filepath.Join(dir, "app.%Y%m%d.log")
+	now := time.Date(2026, time.July, 29, 0, 0, 0, 0, time.UTC)
+
+	old := filepath.Join(dir, "app.20200101.log")
+	if err := os.WriteFile(old, []byte("old"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	oldTime := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	if err := os.Chtimes(old, oldTime, oldTime); err != nil {
+		t.Fatal(err)
+	}
+
+	clock := &fixedClock{t: now}
+	f, err := NewTimeRotateFile(pattern, 24*time.Hour, "", 24*time.Hour, clock)
+	if err != nil {
+		t.Fatalf("NewTimeRotateFile: %v", err)
+	}
+	defer f.Close()
+
+	if isFileExist(old) {
+		t.Fatalf("expected %q to be pruned by MaxAge", old)
+	}
+}